@@ -0,0 +1,13 @@
+// Package common contains types shared across the various WorkOS API
+// clients.
+package common
+
+// ListMetadata contains pagination metadata returned alongside list
+// endpoints that support the before/after cursor convention.
+type ListMetadata struct {
+	// Before is the pagination cursor to receive records before this result.
+	Before string `json:"before"`
+
+	// After is the pagination cursor to receive records after this result.
+	After string `json:"after"`
+}
@@ -0,0 +1,407 @@
+// Package portal provides a client wrapping the WorkOS Admin Portal API.
+package portal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/workos-inc/workos-go/pkg/common"
+)
+
+// ResponseLimit is the default number of records to limit a response to.
+const ResponseLimit = 10
+
+// Intent represents the intent of a Generate Link request.
+type Intent string
+
+// Constants that enumerate the available Intents.
+const (
+	SSO   Intent = "sso"
+	DSync Intent = "dsync"
+)
+
+// ErrOrganizationNotFound is returned when the Organization referenced by a
+// request does not exist.
+var ErrOrganizationNotFound = errors.New("organization not found")
+
+// ErrDuplicateOrganizationDomain is returned when creating or updating an
+// Organization with a domain that is already in use by another Organization.
+var ErrDuplicateOrganizationDomain = errors.New("organization domain is already in use")
+
+// OrganizationDomain represents an Organization's domain.
+type OrganizationDomain struct {
+	// The Organization Domain's unique identifier.
+	ID string `json:"id"`
+
+	// The domain value.
+	Domain string `json:"domain"`
+}
+
+// Organization represents an Organization.
+type Organization struct {
+	// The Organization's unique identifier.
+	ID string `json:"id"`
+
+	// The Organization's name.
+	Name string `json:"name"`
+
+	// The Organization's domains.
+	Domains []OrganizationDomain `json:"domains"`
+}
+
+// ListOrganizationsOpts contains the options to request Organizations.
+type ListOrganizationsOpts struct {
+	// Domains of the Organization.
+	Domains []string
+
+	// Maximum number of records to return.
+	Limit int
+
+	// Pagination cursor to receive records before a provided Organization ID.
+	Before string
+
+	// Pagination cursor to receive records after a provided Organization ID.
+	After string
+}
+
+// ListOrganizationsResponse describes the response structure when requesting
+// Organizations.
+type ListOrganizationsResponse struct {
+	// List of provisioned Organizations.
+	Data []Organization `json:"data"`
+
+	// Cursor pagination options.
+	ListMetadata common.ListMetadata `json:"list_metadata"`
+}
+
+// CreateOrganizationOpts contains the options to create an Organization.
+type CreateOrganizationOpts struct {
+	// Name of the Organization.
+	Name string `json:"name"`
+
+	// Domains of the Organization.
+	Domains []string `json:"domains"`
+
+	// IdempotencyKey, if set, is sent as the request's Idempotency-Key
+	// header so that retries of this exact request (due to a network error
+	// or 5xx response) are safely deduplicated by the API. Leave empty to
+	// have the Client generate one automatically once WithAutoIdempotency
+	// has been called.
+	IdempotencyKey string `json:"-"`
+}
+
+// UpdateOrganizationOpts contains the options to update an Organization.
+type UpdateOrganizationOpts struct {
+	// Organization is the Organization's unique identifier.
+	Organization string `json:"-"`
+
+	// Name of the Organization.
+	Name string `json:"name"`
+
+	// Domains of the Organization.
+	Domains []string `json:"domains"`
+}
+
+// DeleteOrganizationOpts contains the options to delete an Organization.
+type DeleteOrganizationOpts struct {
+	// Organization is the Organization's unique identifier.
+	Organization string
+}
+
+// GenerateLinkOpts contains the options to generate a Portal Link.
+type GenerateLinkOpts struct {
+	// The Intent of the Admin Portal.
+	Intent Intent `json:"intent"`
+
+	// The Organization the Admin Portal will be generated for.
+	Organization string `json:"organization"`
+
+	// The URL the user will be redirected to upon exiting the Admin Portal.
+	ReturnURL string `json:"return_url,omitempty"`
+
+	// IdempotencyKey, if set, is sent as the request's Idempotency-Key
+	// header so that retries of this exact request (due to a network error
+	// or 5xx response) are safely deduplicated by the API. Leave empty to
+	// have the Client generate one automatically once WithAutoIdempotency
+	// has been called.
+	IdempotencyKey string `json:"-"`
+
+	// State, if set, is signed and appended as a `state` query parameter to
+	// ReturnURL, so the handler behind ReturnURL can use VerifyState to
+	// confirm the returning request belongs to the portal session it sent
+	// the admin to and hasn't been replayed or tampered with. Requires
+	// StateSecret or Client.StateSigningKey to be set.
+	State map[string]string `json:"-"`
+
+	// StateSecret is the HMAC-SHA256 key used to sign State. If empty,
+	// Client.StateSigningKey is used instead.
+	StateSecret []byte `json:"-"`
+}
+
+type generateLinkResponse struct {
+	Link string `json:"link"`
+}
+
+// Client represents a client that performs Portal requests to the WorkOS
+// API.
+type Client struct {
+	// The WorkOS API Key. It can be found in https://dashboard.workos.com/api-keys.
+	APIKey string
+
+	// The http.Client that is used to get API requests. If not provided, a
+	// http.Client with a 10 second timeout will be used.
+	HTTPClient *http.Client
+
+	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
+	Endpoint string
+
+	// The function used to encode in JSON. Defaults to json.Marshal.
+	JSONEncode func(v interface{}) ([]byte, error)
+
+	// StateSigningKey is the HMAC-SHA256 key used to sign GenerateLinkOpts.State
+	// when the request doesn't set its own StateSecret.
+	StateSigningKey []byte
+
+	once            sync.Once
+	autoIdempotency bool
+}
+
+// WithAutoIdempotency enables automatic generation of an Idempotency-Key for
+// every CreateOrganization and GenerateLink request that doesn't already
+// specify IdempotencyKey in its options. It returns c to allow chaining off
+// of a Client literal.
+func (c *Client) WithAutoIdempotency() *Client {
+	c.autoIdempotency = true
+	return c
+}
+
+func (c *Client) init() {
+	if c.HTTPClient == nil {
+		c.HTTPClient = &http.Client{Timeout: time.Second * 10}
+	}
+
+	if c.Endpoint == "" {
+		c.Endpoint = "https://api.workos.com"
+	}
+	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+
+	if c.JSONEncode == nil {
+		c.JSONEncode = json.Marshal
+	}
+}
+
+// ListOrganizations gets a list of Organizations.
+func (c *Client) ListOrganizations(ctx context.Context, opts ListOrganizationsOpts) (ListOrganizationsResponse, error) {
+	c.once.Do(c.init)
+
+	limit := opts.Limit
+	if limit == 0 {
+		limit = ResponseLimit
+	}
+
+	query := url.Values{
+		"limit":  {strconv.Itoa(limit)},
+		"before": {opts.Before},
+		"after":  {opts.After},
+	}
+	for _, domain := range opts.Domains {
+		query.Add("domains[]", domain)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.Endpoint+"/organizations?"+query.Encode(), nil)
+	if err != nil {
+		return ListOrganizationsResponse{}, err
+	}
+	req = req.WithContext(ctx)
+	c.setCommonHeaders(req)
+
+	var body ListOrganizationsResponse
+	if err := c.do(req, &body); err != nil {
+		return ListOrganizationsResponse{}, err
+	}
+
+	return body, nil
+}
+
+// CreateOrganization creates an Organization.
+func (c *Client) CreateOrganization(ctx context.Context, opts CreateOrganizationOpts) (Organization, error) {
+	c.once.Do(c.init)
+
+	data, err := c.JSONEncode(opts)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.Endpoint+"/organizations", bytes.NewBuffer(data))
+	if err != nil {
+		return Organization{}, err
+	}
+	req = req.WithContext(ctx)
+	c.setCommonHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	key := c.idempotencyKey(opts.IdempotencyKey)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	var organization Organization
+	if err := c.doIdempotent(key, req, &organization); err != nil {
+		return Organization{}, err
+	}
+
+	return organization, nil
+}
+
+// UpdateOrganization updates an Organization.
+func (c *Client) UpdateOrganization(ctx context.Context, opts UpdateOrganizationOpts) (Organization, error) {
+	c.once.Do(c.init)
+
+	data, err := c.JSONEncode(opts)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, c.Endpoint+"/organizations/"+opts.Organization, bytes.NewBuffer(data))
+	if err != nil {
+		return Organization{}, err
+	}
+	req = req.WithContext(ctx)
+	c.setCommonHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	var organization Organization
+	if err := c.do(req, &organization); err != nil {
+		return Organization{}, err
+	}
+
+	return organization, nil
+}
+
+// DeleteOrganization deletes an Organization.
+func (c *Client) DeleteOrganization(ctx context.Context, opts DeleteOrganizationOpts) error {
+	c.once.Do(c.init)
+
+	req, err := http.NewRequest(http.MethodDelete, c.Endpoint+"/organizations/"+opts.Organization, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	c.setCommonHeaders(req)
+
+	return c.do(req, nil)
+}
+
+// GenerateLink generates a Portal Link scoped to the given Organization.
+func (c *Client) GenerateLink(ctx context.Context, opts GenerateLinkOpts) (string, error) {
+	c.once.Do(c.init)
+
+	if len(opts.State) > 0 {
+		stateKey := opts.StateSecret
+		if len(stateKey) == 0 {
+			stateKey = c.StateSigningKey
+		}
+		if len(stateKey) == 0 {
+			return "", errors.New("workos: GenerateLinkOpts.State requires StateSecret or Client.StateSigningKey to be set")
+		}
+
+		returnURL, err := signedReturnURL(opts, stateKey)
+		if err != nil {
+			return "", err
+		}
+		opts.ReturnURL = returnURL
+	}
+
+	data, err := c.JSONEncode(opts)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.Endpoint+"/portal/generate_link", bytes.NewBuffer(data))
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	c.setCommonHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+	key := c.idempotencyKey(opts.IdempotencyKey)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	var body generateLinkResponse
+	if err := c.doIdempotent(key, req, &body); err != nil {
+		return "", err
+	}
+
+	return body.Link, nil
+}
+
+// idempotencyKey returns provided if it is non-empty, otherwise it generates
+// a UUID v4 when the Client has WithAutoIdempotency enabled, otherwise "".
+func (c *Client) idempotencyKey(provided string) string {
+	if provided != "" {
+		return provided
+	}
+	if c.autoIdempotency {
+		return uuid.NewString()
+	}
+	return ""
+}
+
+func (c *Client) setCommonHeaders(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("User-Agent", "workos-go/1.0.0")
+}
+
+// do sends req and decodes the response body into v. If v is nil, the
+// response body is discarded. Non-2xx responses are translated into an
+// error, using a typed sentinel when the API signals a condition callers
+// are expected to handle (conflicting domains, missing resources).
+func (c *Client) do(req *http.Request, v interface{}) error {
+	if c.APIKey == "" {
+		return errors.New("workos: APIKey is required")
+	}
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return &retryableError{err: err}
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode >= 300 {
+		switch res.StatusCode {
+		case http.StatusNotFound:
+			return ErrOrganizationNotFound
+		case http.StatusConflict:
+			return ErrDuplicateOrganizationDomain
+		default:
+			err := fmt.Errorf("workos: request to %s returned status %d: %s", req.URL.Path, res.StatusCode, strings.TrimSpace(string(body)))
+			if res.StatusCode >= 500 {
+				return &retryableError{err: err}
+			}
+			return err
+		}
+	}
+
+	if v == nil || len(body) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(body, v)
+}
@@ -6,8 +6,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/workos-inc/workos-go/pkg/common"
 )
@@ -226,6 +228,448 @@ func createOrganizationTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+func TestCreateOrganizationIdempotencyRetry(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		attempt := attempts
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		mu.Unlock()
+
+		if attempt < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, err := json.Marshal(Organization{ID: "organization_id", Name: "Foo Corp"})
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	organization, err := client.CreateOrganization(context.Background(), CreateOrganizationOpts{
+		Name:           "Foo Corp",
+		IdempotencyKey: "a-fixed-key",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "organization_id", organization.ID)
+	require.Equal(t, []string{"a-fixed-key", "a-fixed-key", "a-fixed-key"}, keys)
+}
+
+func TestCreateOrganizationAutoIdempotency(t *testing.T) {
+	var key string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get("Idempotency-Key")
+
+		body, err := json.Marshal(Organization{ID: "organization_id", Name: "Foo Corp"})
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := (&Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}).WithAutoIdempotency()
+
+	_, err := client.CreateOrganization(context.Background(), CreateOrganizationOpts{Name: "Foo Corp"})
+	require.NoError(t, err)
+
+	_, err = uuid.Parse(key)
+	require.NoError(t, err)
+}
+
+func TestGenerateLinkIdempotencyRetryReusesGeneratedKey(t *testing.T) {
+	var mu sync.Mutex
+	var attempts int
+	var keys []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		attempts++
+		attempt := attempts
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		mu.Unlock()
+
+		if attempt < 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		body, err := json.Marshal(generateLinkResponse{Link: "https://id.workos.test/portal/launch?secret=1234"})
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := (&Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}).WithAutoIdempotency()
+
+	link, err := client.GenerateLink(context.Background(), GenerateLinkOpts{
+		Intent:       SSO,
+		Organization: "organization_id",
+		ReturnURL:    "https://foo-corp.app.com/settings",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "https://id.workos.test/portal/launch?secret=1234", link)
+	require.Len(t, keys, 2)
+	require.NotEmpty(t, keys[0])
+	require.Equal(t, keys[0], keys[1])
+}
+
+func TestUpdateOrganization(t *testing.T) {
+	tests := []struct {
+		scenario string
+		client   *Client
+		options  UpdateOrganizationOpts
+		expected Organization
+		err      bool
+	}{
+		{
+			scenario: "Request without API Key returns an error",
+			client:   &Client{},
+			err:      true,
+		},
+		{
+			scenario: "Request returns Organization",
+			client: &Client{
+				APIKey: "test",
+			},
+			options: UpdateOrganizationOpts{
+				Organization: "organization_id",
+				Name:         "Foo Corp",
+				Domains:      []string{"foo-corp.com"},
+			},
+			expected: Organization{
+				ID:   "organization_id",
+				Name: "Foo Corp",
+				Domains: []OrganizationDomain{
+					OrganizationDomain{
+						ID:     "organization_domain_id",
+						Domain: "foo-corp.com",
+					},
+				},
+			},
+		},
+		{
+			scenario: "Request with duplicate Organization Domain returns error",
+			client: &Client{
+				APIKey: "test",
+			},
+			err: true,
+			options: UpdateOrganizationOpts{
+				Organization: "organization_id",
+				Name:         "Foo Corp",
+				Domains:      []string{"duplicate.com"},
+			},
+		},
+		{
+			scenario: "Request for nonexistent Organization returns error",
+			client: &Client{
+				APIKey: "test",
+			},
+			err: true,
+			options: UpdateOrganizationOpts{
+				Organization: "missing_id",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(updateOrganizationTestHandler))
+			defer server.Close()
+
+			client := test.client
+			client.Endpoint = server.URL
+			client.HTTPClient = server.Client()
+
+			organization, err := client.UpdateOrganization(context.Background(), test.options)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, organization)
+		})
+	}
+}
+
+func updateOrganizationTestHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if auth != "Bearer test" {
+		http.Error(w, "bad auth", http.StatusUnauthorized)
+		return
+	}
+
+	if strings.HasSuffix(r.URL.Path, "missing_id") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	var opts UpdateOrganizationOpts
+	json.NewDecoder(r.Body).Decode(&opts)
+	for _, domain := range opts.Domains {
+		if domain == "duplicate.com" {
+			http.Error(w, "duplicate domain", http.StatusConflict)
+			return
+		}
+	}
+
+	body, err := json.Marshal(
+		Organization{
+			ID:   "organization_id",
+			Name: "Foo Corp",
+			Domains: []OrganizationDomain{
+				OrganizationDomain{
+					ID:     "organization_domain_id",
+					Domain: "foo-corp.com",
+				},
+			},
+		})
+
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func TestDeleteOrganization(t *testing.T) {
+	tests := []struct {
+		scenario string
+		client   *Client
+		options  DeleteOrganizationOpts
+		err      bool
+	}{
+		{
+			scenario: "Request without API Key returns an error",
+			client:   &Client{},
+			err:      true,
+		},
+		{
+			scenario: "Request deletes the Organization",
+			client: &Client{
+				APIKey: "test",
+			},
+			options: DeleteOrganizationOpts{
+				Organization: "organization_id",
+			},
+		},
+		{
+			scenario: "Request for nonexistent Organization returns error",
+			client: &Client{
+				APIKey: "test",
+			},
+			err: true,
+			options: DeleteOrganizationOpts{
+				Organization: "missing_id",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(deleteOrganizationTestHandler))
+			defer server.Close()
+
+			client := test.client
+			client.Endpoint = server.URL
+			client.HTTPClient = server.Client()
+
+			err := client.DeleteOrganization(context.Background(), test.options)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func deleteOrganizationTestHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if auth != "Bearer test" {
+		http.Error(w, "bad auth", http.StatusUnauthorized)
+		return
+	}
+
+	if strings.HasSuffix(r.URL.Path, "missing_id") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestOrganizationsIter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(organizationsIterTestHandler))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	iter := client.OrganizationsIter(context.Background(), ListOrganizationsOpts{Limit: 1})
+
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.Organization().ID)
+	}
+
+	require.NoError(t, iter.Err())
+	require.Equal(t, []string{"organization_1", "organization_2", "organization_3"}, ids)
+}
+
+func TestOrganizationsIterContextCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(organizationsIterTestHandler))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	iter := client.OrganizationsIter(ctx, ListOrganizationsOpts{Limit: 1})
+
+	require.False(t, iter.Next())
+	require.Error(t, iter.Err())
+}
+
+func TestListOrganizationsAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(organizationsIterTestHandler))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	organizations, err := client.ListOrganizationsAll(context.Background(), ListOrganizationsOpts{Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"organization_1", "organization_2", "organization_3"}, []string{
+		organizations[0].ID, organizations[1].ID, organizations[2].ID,
+	})
+}
+
+func TestOrganizationsIterSkipsEmptyIntermediatePage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(organizationsIterEmptyPageTestHandler))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	iter := client.OrganizationsIter(context.Background(), ListOrganizationsOpts{Limit: 1})
+
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.Organization().ID)
+	}
+
+	require.NoError(t, iter.Err())
+	require.Equal(t, []string{"organization_1", "organization_3"}, ids)
+}
+
+func organizationsIterEmptyPageTestHandler(w http.ResponseWriter, r *http.Request) {
+	var resp ListOrganizationsResponse
+
+	switch r.URL.Query().Get("after") {
+	case "":
+		resp = ListOrganizationsResponse{
+			Data:         []Organization{{ID: "organization_1", Name: "Foo Corp"}},
+			ListMetadata: common.ListMetadata{After: "cursor1"},
+		}
+	case "cursor1":
+		resp = ListOrganizationsResponse{
+			Data:         nil,
+			ListMetadata: common.ListMetadata{After: "cursor2"},
+		}
+	case "cursor2":
+		resp = ListOrganizationsResponse{
+			Data:         []Organization{{ID: "organization_3", Name: "Baz Corp"}},
+			ListMetadata: common.ListMetadata{After: ""},
+		}
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func organizationsIterTestHandler(w http.ResponseWriter, r *http.Request) {
+	var resp ListOrganizationsResponse
+
+	switch r.URL.Query().Get("after") {
+	case "":
+		resp = ListOrganizationsResponse{
+			Data:         []Organization{{ID: "organization_1", Name: "Foo Corp"}},
+			ListMetadata: common.ListMetadata{After: "organization_1"},
+		}
+	case "organization_1":
+		resp = ListOrganizationsResponse{
+			Data:         []Organization{{ID: "organization_2", Name: "Bar Corp"}},
+			ListMetadata: common.ListMetadata{After: "organization_2"},
+		}
+	case "organization_2":
+		resp = ListOrganizationsResponse{
+			Data:         []Organization{{ID: "organization_3", Name: "Baz Corp"}},
+			ListMetadata: common.ListMetadata{After: ""},
+		}
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
 func TestGenerateLink(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -0,0 +1,117 @@
+package portal
+
+import "context"
+
+// ListOrganizationsAll retrieves every Organization matching opts,
+// transparently following the `after` pagination cursor until the API
+// reports no further pages.
+func (c *Client) ListOrganizationsAll(ctx context.Context, opts ListOrganizationsOpts) ([]Organization, error) {
+	var organizations []Organization
+
+	iter := c.OrganizationsIter(ctx, opts)
+	for iter.Next() {
+		organizations = append(organizations, iter.Organization())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return organizations, nil
+}
+
+// OrganizationIterator iterates over every Organization matching a
+// ListOrganizationsOpts, fetching additional pages from the API as needed.
+// It should be driven with Next, which reports whether Organization holds a
+// valid result:
+//
+//	iter := client.OrganizationsIter(ctx, opts)
+//	for iter.Next() {
+//		organization := iter.Organization()
+//	}
+//	if err := iter.Err(); err != nil {
+//		// handle err
+//	}
+type OrganizationIterator struct {
+	ctx    context.Context
+	client *Client
+	opts   ListOrganizationsOpts
+
+	page  []Organization
+	index int
+	after string
+	done  bool
+	err   error
+}
+
+// OrganizationsIter returns an OrganizationIterator that lists every
+// Organization matching opts. The page size fetched from the API is
+// controlled by opts.Limit.
+func (c *Client) OrganizationsIter(ctx context.Context, opts ListOrganizationsOpts) *OrganizationIterator {
+	return &OrganizationIterator{
+		ctx:    ctx,
+		client: c,
+		opts:   opts,
+		after:  opts.After,
+	}
+}
+
+// Next advances the iterator to the next Organization, transparently
+// fetching the next page from the API once the current page is exhausted.
+// It returns false once iteration is complete, the context is cancelled, or
+// a request fails; callers should inspect Err to distinguish the latter two
+// cases from ordinary exhaustion.
+func (it *OrganizationIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	if it.index < len(it.page)-1 {
+		it.index++
+		return true
+	}
+
+	for {
+		if it.done {
+			return false
+		}
+
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		opts := it.opts
+		opts.After = it.after
+
+		resp, err := it.client.ListOrganizations(it.ctx, opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.page = resp.Data
+		it.index = 0
+		it.after = resp.ListMetadata.After
+		it.done = it.after == ""
+
+		if len(it.page) > 0 {
+			return true
+		}
+	}
+}
+
+// Organization returns the Organization at the iterator's current position.
+// It must only be called after a call to Next that returned true.
+func (it *OrganizationIterator) Organization() Organization {
+	return it.page[it.index]
+}
+
+// Err returns the first error, if any, encountered while iterating.
+func (it *OrganizationIterator) Err() error {
+	return it.err
+}
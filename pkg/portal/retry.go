@@ -0,0 +1,82 @@
+package portal
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Retry tuning for idempotent requests: up to maxIdempotentRetries
+// additional attempts, doubling the delay each time up to maxRetryBackoff.
+const (
+	maxIdempotentRetries = 3
+	initialRetryBackoff  = 200 * time.Millisecond
+	maxRetryBackoff      = 2 * time.Second
+)
+
+// retryableError marks an error returned by do as safe to retry: it
+// represents either a connection error or a 5xx response, neither of which
+// confirms whether the request was actually applied.
+type retryableError struct {
+	err error
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// doIdempotent sends req via do. If key is non-empty, the request is known
+// to be idempotent (it carries an Idempotency-Key the API will use to
+// deduplicate it) and is retried with capped exponential backoff on
+// connection errors or 5xx responses. If key is empty, req is sent exactly
+// once.
+func (c *Client) doIdempotent(key string, req *http.Request, v interface{}) error {
+	if key == "" {
+		return c.do(req, v)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body.Close()
+	}
+
+	backoff := initialRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		if body != nil {
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		err := c.do(req, v)
+		if err == nil {
+			return nil
+		}
+
+		var retryable *retryableError
+		if !errors.As(err, &retryable) || attempt == maxIdempotentRetries {
+			return err
+		}
+
+		select {
+		case <-req.Context().Done():
+			return req.Context().Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package portal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// stateTokenTTL is how long a signed portal state token remains valid after
+// GenerateLink issues it.
+const stateTokenTTL = 10 * time.Minute
+
+// ErrStateExpired is returned by VerifyState when the token's exp claim has
+// passed.
+var ErrStateExpired = errors.New("portal: state token has expired")
+
+// ErrStateTampered is returned by VerifyState when the token's signature
+// does not match its payload.
+var ErrStateTampered = errors.New("portal: state token signature is invalid")
+
+// ErrStateOrganizationMismatch is returned by StateClaims.Matches when the
+// claims were issued for a different Organization than expected.
+var ErrStateOrganizationMismatch = errors.New("portal: state token was issued for a different organization")
+
+// ErrStateIntentMismatch is returned by StateClaims.Matches when the claims
+// were issued for a different Intent than expected.
+var ErrStateIntentMismatch = errors.New("portal: state token was issued for a different intent")
+
+// StateClaims are the claims carried by a signed portal state token, as
+// produced by GenerateLink and decoded by VerifyState.
+type StateClaims struct {
+	// State is the caller-supplied state being round-tripped through the
+	// Admin Portal.
+	State map[string]string `json:"state"`
+
+	// Organization is the Organization the Admin Portal session was scoped
+	// to.
+	Organization string `json:"org"`
+
+	// Intent is the Intent the Admin Portal session was generated for.
+	Intent Intent `json:"intent"`
+
+	// IssuedAt is when the token was signed, as a Unix timestamp.
+	IssuedAt int64 `json:"iat"`
+
+	// ExpiresAt is when the token stops being valid, as a Unix timestamp.
+	ExpiresAt int64 `json:"exp"`
+}
+
+// Matches reports whether the claims were issued for the given Organization
+// and Intent. Return-URL handlers should call this after VerifyState to
+// confirm the token being presented belongs to the portal session they
+// expect, rather than one replayed from an unrelated organization or flow.
+func (c StateClaims) Matches(organization string, intent Intent) error {
+	if c.Organization != organization {
+		return ErrStateOrganizationMismatch
+	}
+	if c.Intent != intent {
+		return ErrStateIntentMismatch
+	}
+	return nil
+}
+
+// signedReturnURL signs opts.State (and the Organization/Intent it was
+// requested for) with key and returns opts.ReturnURL with the resulting
+// token appended as a `state` query parameter.
+func signedReturnURL(opts GenerateLinkOpts, key []byte) (string, error) {
+	if opts.ReturnURL == "" {
+		return "", errors.New("workos: GenerateLinkOpts.State requires ReturnURL to be set")
+	}
+
+	now := time.Now()
+	claims := StateClaims{
+		State:        opts.State,
+		Organization: opts.Organization,
+		Intent:       opts.Intent,
+		IssuedAt:     now.Unix(),
+		ExpiresAt:    now.Add(stateTokenTTL).Unix(),
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	token := base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	u, err := url.Parse(opts.ReturnURL)
+	if err != nil {
+		return "", fmt.Errorf("workos: invalid ReturnURL: %w", err)
+	}
+
+	query := u.Query()
+	query.Set("state", token)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// VerifyState constant-time verifies the HMAC-SHA256 signature of rawState,
+// the `state` query parameter appended to ReturnURL by GenerateLink, using
+// the same key passed as GenerateLinkOpts.StateSecret or set as
+// Client.StateSigningKey. It rejects tokens whose signature doesn't match
+// or whose exp claim has passed, and otherwise returns the decoded claims.
+func VerifyState(rawState string, key []byte) (StateClaims, error) {
+	payloadPart, sigPart, ok := strings.Cut(rawState, ".")
+	if !ok {
+		return StateClaims{}, errors.New("portal: malformed state token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return StateClaims{}, fmt.Errorf("portal: malformed state token: %w", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(sigPart)
+	if err != nil {
+		return StateClaims{}, fmt.Errorf("portal: malformed state token: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	if subtle.ConstantTimeCompare(sig, expected) != 1 {
+		return StateClaims{}, ErrStateTampered
+	}
+
+	var claims StateClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return StateClaims{}, fmt.Errorf("portal: malformed state token: %w", err)
+	}
+
+	if time.Now().Unix() > claims.ExpiresAt {
+		return StateClaims{}, ErrStateExpired
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,137 @@
+package portal
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateLinkSignedState(t *testing.T) {
+	var posted GenerateLinkOpts
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+
+		body, err := json.Marshal(generateLinkResponse{Link: "https://id.workos.test/portal/launch?secret=1234"})
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	key := []byte("super-secret-signing-key")
+
+	_, err := client.GenerateLink(context.Background(), GenerateLinkOpts{
+		Intent:       SSO,
+		Organization: "organization_id",
+		ReturnURL:    "https://foo-corp.app.com/settings",
+		State:        map[string]string{"admin_id": "admin_1"},
+		StateSecret:  key,
+	})
+	require.NoError(t, err)
+
+	u, err := url.Parse(posted.ReturnURL)
+	require.NoError(t, err)
+
+	rawState := u.Query().Get("state")
+	require.NotEmpty(t, rawState)
+
+	claims, err := VerifyState(rawState, key)
+	require.NoError(t, err)
+	require.Equal(t, "organization_id", claims.Organization)
+	require.Equal(t, SSO, claims.Intent)
+	require.Equal(t, map[string]string{"admin_id": "admin_1"}, claims.State)
+}
+
+func TestGenerateLinkStateWithoutSecretReturnsError(t *testing.T) {
+	client := &Client{APIKey: "test"}
+
+	_, err := client.GenerateLink(context.Background(), GenerateLinkOpts{
+		Intent:       SSO,
+		Organization: "organization_id",
+		ReturnURL:    "https://foo-corp.app.com/settings",
+		State:        map[string]string{"admin_id": "admin_1"},
+	})
+	require.Error(t, err)
+}
+
+func TestGenerateLinkStateWithoutReturnURLReturnsError(t *testing.T) {
+	client := &Client{APIKey: "test"}
+
+	_, err := client.GenerateLink(context.Background(), GenerateLinkOpts{
+		Intent:       SSO,
+		Organization: "organization_id",
+		State:        map[string]string{"admin_id": "admin_1"},
+		StateSecret:  []byte("super-secret-signing-key"),
+	})
+	require.Error(t, err)
+}
+
+func signedTestToken(t *testing.T, claims StateClaims, key []byte) string {
+	t.Helper()
+
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyStateTampered(t *testing.T) {
+	token := signedTestToken(t, StateClaims{
+		Organization: "organization_id",
+		Intent:       SSO,
+		IssuedAt:     time.Now().Unix(),
+		ExpiresAt:    time.Now().Add(time.Minute).Unix(),
+	}, []byte("signing-key"))
+
+	_, err := VerifyState(token, []byte("a-different-key"))
+	require.ErrorIs(t, err, ErrStateTampered)
+}
+
+func TestVerifyStateExpired(t *testing.T) {
+	key := []byte("signing-key")
+	token := signedTestToken(t, StateClaims{
+		Organization: "organization_id",
+		Intent:       SSO,
+		IssuedAt:     time.Now().Add(-time.Hour).Unix(),
+		ExpiresAt:    time.Now().Add(-time.Minute).Unix(),
+	}, key)
+
+	_, err := VerifyState(token, key)
+	require.ErrorIs(t, err, ErrStateExpired)
+}
+
+func TestStateClaimsMatchesRejectsOrganizationAndIntentMismatch(t *testing.T) {
+	key := []byte("signing-key")
+	token := signedTestToken(t, StateClaims{
+		Organization: "organization_a",
+		Intent:       SSO,
+		IssuedAt:     time.Now().Unix(),
+		ExpiresAt:    time.Now().Add(time.Minute).Unix(),
+	}, key)
+
+	claims, err := VerifyState(token, key)
+	require.NoError(t, err)
+
+	require.NoError(t, claims.Matches("organization_a", SSO))
+	require.ErrorIs(t, claims.Matches("organization_b", SSO), ErrStateOrganizationMismatch)
+	require.ErrorIs(t, claims.Matches("organization_a", DSync), ErrStateIntentMismatch)
+}